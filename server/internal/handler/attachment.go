@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"snail-server/internal/service"
+)
+
+type AttachmentHandler struct {
+	attachmentService *service.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
+
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少上传文件"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.Upload(c.Request.Context(), userID, &service.UploadAttachmentInput{
+		Filename: fileHeader.Filename,
+		MimeType: fileHeader.Header.Get("Content-Type"),
+		Size:     fileHeader.Size,
+		Reader:   file,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+func (h *AttachmentHandler) Get(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	attachmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的附件ID"})
+		return
+	}
+
+	attachment, url, err := h.attachmentService.GetDownload(c.Request.Context(), userID, attachmentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attachment": attachment,
+		"url":        url,
+	})
+}
+
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	attachmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的附件ID"})
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(c.Request.Context(), userID, attachmentID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
 	"snail-server/internal/service"
 )
 
@@ -22,7 +25,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.Register(&input)
+	resp, err := h.authService.Register(&input, sessionMeta(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -38,7 +41,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.Login(&input)
+	resp, err := h.authService.Login(&input, sessionMeta(c))
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
@@ -49,7 +52,11 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 func (h *AuthHandler) SendEmailCode(c *gin.Context) {
 	var input service.EmailCodeInput
-	if err := c.ShouldBindJSON(&input); err != nil {
+	// This route sits behind middleware.RateLimit, whose ByJSONField key
+	// functions already drained c.Request.Body via ShouldBindBodyWith;
+	// binding the same way here reads gin's cached copy instead of the
+	// now-empty request body.
+	if err := c.ShouldBindBodyWith(&input, binding.JSON); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -69,7 +76,28 @@ func (h *AuthHandler) EmailLogin(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.EmailLogin(&input)
+	resp, err := h.authService.EmailLogin(&input, sessionMeta(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RefreshInput carries the refresh token to exchange for a new token pair.
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var input RefreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.authService.Refresh(input.RefreshToken)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
@@ -77,3 +105,24 @@ func (h *AuthHandler) EmailLogin(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	sessionID := c.MustGet("sessionID").(uuid.UUID)
+	jti := c.MustGet("jti").(string)
+	expiresAt := c.MustGet("tokenExpiresAt").(time.Time)
+
+	if err := h.authService.Logout(sessionID, jti, expiresAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已退出登录"})
+}
+
+// sessionMeta captures the device/client info recorded on a new session.
+func sessionMeta(c *gin.Context) service.SessionMeta {
+	return service.SessionMeta{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
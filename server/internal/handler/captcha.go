@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"snail-server/pkg/captcha"
+)
+
+// Captcha handles GET /api/v1/captcha, returning a new math captcha.
+func Captcha(c *gin.Context) {
+	id, imageBase64, err := captcha.Generate()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"captcha_id":   id,
+		"image_base64": imageBase64,
+	})
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"snail-server/internal/service"
+	"snail-server/pkg/oauth"
+)
+
+// oauthCookieName is the browser-side cookie used to correlate the
+// /login redirect with its matching /callback request.
+const oauthCookieName = "oauth_session"
+
+type OAuthHandler struct {
+	authService *service.AuthService
+	states      *oauth.StateStore
+}
+
+func NewOAuthHandler(authService *service.AuthService, states *oauth.StateStore) *OAuthHandler {
+	return &OAuthHandler{authService: authService, states: states}
+}
+
+// Login redirects the browser to the provider's authorize endpoint,
+// starting the authorization-code + PKCE flow.
+func (h *OAuthHandler) Login(c *gin.Context) {
+	provider, ok := oauth.Get(c.Param("provider"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不支持的登录方式"})
+		return
+	}
+
+	state, err := oauth.NewState()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	verifier, err := oauth.NewCodeVerifier()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	cookieValue, err := oauth.NewState()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.states.Put(cookieValue, provider.Name(), state, verifier)
+	c.SetCookie(oauthCookieName, cookieValue, 600, "/", "", false, true)
+
+	c.Redirect(http.StatusFound, provider.AuthURL(state, oauth.CodeChallengeS256(verifier)))
+}
+
+// Callback exchanges the authorization code for the user's profile and
+// logs them in (creating an account on first login).
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	provider, ok := oauth.Get(c.Param("provider"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不支持的登录方式"})
+		return
+	}
+
+	cookieValue, err := c.Cookie(oauthCookieName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "登录状态丢失，请重新登录"})
+		return
+	}
+	c.SetCookie(oauthCookieName, "", -1, "/", "", false, true)
+
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少 code 或 state 参数"})
+		return
+	}
+
+	verifier, err := h.states.Take(cookieValue, provider.Name(), state)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	info, err := provider.Exchange(c.Request.Context(), code, verifier)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.authService.OAuthLogin(provider.Name(), info, sessionMeta(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"snail-server/internal/service"
+)
+
+type SearchHandler struct {
+	taskService *service.TaskService
+}
+
+func NewSearchHandler(taskService *service.TaskService) *SearchHandler {
+	return &SearchHandler{taskService: taskService}
+}
+
+// Search handles GET /api/v1/search?q=...&list_id=...&tag=...&due_before=...
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	input := &service.SearchTasksInput{
+		Query: c.Query("q"),
+		Tag:   c.Query("tag"),
+	}
+
+	if listIDParam := c.Query("list_id"); listIDParam != "" {
+		listID, err := uuid.Parse(listIDParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的清单ID"})
+			return
+		}
+		input.ListID = &listID
+	}
+
+	if dueBeforeParam := c.Query("due_before"); dueBeforeParam != "" {
+		dueBefore, err := time.Parse(time.RFC3339, dueBeforeParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "due_before 格式应为 RFC3339"})
+			return
+		}
+		input.DueBefore = &dueBefore
+	}
+
+	hits, err := h.taskService.Search(c.Request.Context(), userID, input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, hits)
+}
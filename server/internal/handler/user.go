@@ -10,10 +10,11 @@ import (
 
 type UserHandler struct {
 	userService *service.UserService
+	authService *service.AuthService
 }
 
-func NewUserHandler(userService *service.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+func NewUserHandler(userService *service.UserService, authService *service.AuthService) *UserHandler {
+	return &UserHandler{userService: userService, authService: authService}
 }
 
 func (h *UserHandler) GetProfile(c *gin.Context) {
@@ -62,3 +63,83 @@ func (h *UserHandler) UpdatePassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "密码修改成功"})
 }
+
+func (h *UserHandler) GetPasskeys(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	passkeys, err := h.authService.ListPasskeys(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, passkeys)
+}
+
+func (h *UserHandler) GetSessions(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	sessions, err := h.authService.ListSessions(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (h *UserHandler) DeleteSession(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的会话ID"})
+		return
+	}
+
+	if err := h.authService.RevokeSession(userID, sessionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已退出该设备"})
+}
+
+func (h *UserHandler) GetIdentities(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	identities, err := h.authService.ListIdentities(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, identities)
+}
+
+func (h *UserHandler) DeleteIdentity(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	provider := c.Param("provider")
+
+	if err := h.authService.UnlinkIdentity(userID, provider); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已解绑该登录方式"})
+}
+
+func (h *UserHandler) DeletePasskey(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	credID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的通行密钥ID"})
+		return
+	}
+
+	if err := h.authService.DeletePasskey(userID, credID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"snail-server/internal/service"
+)
+
+// webauthnCookieName carries the ceremony's challenge/session correlation
+// value between a begin call and its matching finish call.
+const webauthnCookieName = "webauthn_session"
+
+type WebAuthnHandler struct {
+	authService *service.AuthService
+}
+
+func NewWebAuthnHandler(authService *service.AuthService) *WebAuthnHandler {
+	return &WebAuthnHandler{authService: authService}
+}
+
+func (h *WebAuthnHandler) BeginRegister(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	creation, cookieValue, err := h.authService.BeginPasskeyRegistration(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.SetCookie(webauthnCookieName, cookieValue, 300, "/", "", false, true)
+	c.JSON(http.StatusOK, creation)
+}
+
+func (h *WebAuthnHandler) FinishRegister(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	cookieValue, err := c.Cookie(webauthnCookieName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "验证会话丢失，请重新发起"})
+		return
+	}
+	c.SetCookie(webauthnCookieName, "", -1, "/", "", false, true)
+
+	if err := h.authService.FinishPasskeyRegistration(userID, cookieValue, c.Request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "通行密钥添加成功"})
+}
+
+func (h *WebAuthnHandler) BeginLogin(c *gin.Context) {
+	assertion, cookieValue, err := h.authService.BeginPasskeyLogin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.SetCookie(webauthnCookieName, cookieValue, 300, "/", "", false, true)
+	c.JSON(http.StatusOK, assertion)
+}
+
+func (h *WebAuthnHandler) FinishLogin(c *gin.Context) {
+	cookieValue, err := c.Cookie(webauthnCookieName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "验证会话丢失，请重新发起"})
+		return
+	}
+	c.SetCookie(webauthnCookieName, "", -1, "/", "", false, true)
+
+	resp, err := h.authService.FinishPasskeyLogin(cookieValue, c.Request, sessionMeta(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"snail-server/internal/repository"
+	"snail-server/pkg/jwt"
+	"snail-server/pkg/realtime"
+)
+
+// WSHandler upgrades GET /api/v1/ws to a WebSocket. Browsers can't set
+// custom headers on the upgrade request, so the JWT is accepted either as
+// a `token` query param or via the Sec-WebSocket-Protocol header (some
+// WebSocket clients only support passing credentials that way).
+type WSHandler struct {
+	revokedTokenRepo *repository.RevokedTokenRepository
+}
+
+func NewWSHandler(revokedTokenRepo *repository.RevokedTokenRepository) *WSHandler {
+	return &WSHandler{revokedTokenRepo: revokedTokenRepo}
+}
+
+func (h *WSHandler) WS(c *gin.Context) {
+	protocol := firstProtocol(c.GetHeader("Sec-WebSocket-Protocol"))
+
+	token := c.Query("token")
+	if token == "" {
+		token = protocol
+	} else {
+		// Token came from the query string, so there is no client-offered
+		// subprotocol of ours to echo back.
+		protocol = ""
+	}
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "缺少访问令牌"})
+		return
+	}
+
+	claims, err := jwt.ParseToken(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "访问令牌无效"})
+		return
+	}
+
+	if revoked, err := h.revokedTokenRepo.Exists(claims.ID); err == nil && revoked {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "访问令牌已失效"})
+		return
+	}
+
+	if err := realtime.Default().Serve(c.Writer, c.Request, claims.UserID, protocol); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+}
+
+func firstProtocol(header string) string {
+	parts := strings.Split(header, ",")
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(parts[0])
+}
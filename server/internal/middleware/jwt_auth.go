@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"snail-server/internal/repository"
+	"snail-server/pkg/jwt"
+)
+
+// revokedCache is a short-lived in-memory cache of jti values already
+// confirmed revoked, so a logged-out token doesn't cost a
+// database round trip on every single request that retries it.
+type revokedCache struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+const revokedCacheTTL = time.Minute
+
+func newRevokedCache() *revokedCache {
+	c := &revokedCache{entries: make(map[string]time.Time)}
+	go c.sweepLoop()
+	return c
+}
+
+func (c *revokedCache) Contains(jti string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[jti]
+	return ok
+}
+
+func (c *revokedCache) Add(jti string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[jti] = time.Now().Add(revokedCacheTTL)
+}
+
+func (c *revokedCache) sweepLoop() {
+	ticker := time.NewTicker(revokedCacheTTL)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		c.mu.Lock()
+		for jti, expiresAt := range c.entries {
+			if now.After(expiresAt) {
+				delete(c.entries, jti)
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+// JWTAuth validates the bearer access token on every request, rejecting
+// ones that are expired, malformed, revoked (denylisted in
+// revokedTokenRepo — checked through an in-memory cache first so a busy
+// client isn't a database hit per request), or whose session was killed
+// via "log out this device": that only sets Session.RevokedAt, it doesn't
+// denylist the still-valid access token's jti, so this is checked directly
+// against sessionRepo on every request that the jti cache has not already
+// rejected; once a session is found revoked, its token's jti is cached too.
+func JWTAuth(revokedTokenRepo *repository.RevokedTokenRepository, sessionRepo *repository.SessionRepository) gin.HandlerFunc {
+	cache := newRevokedCache()
+
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "缺少访问令牌"})
+			c.Abort()
+			return
+		}
+		token := strings.TrimPrefix(header, "Bearer ")
+
+		claims, err := jwt.ParseToken(token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "访问令牌无效或已过期"})
+			c.Abort()
+			return
+		}
+
+		if cache.Contains(claims.ID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "访问令牌已失效"})
+			c.Abort()
+			return
+		}
+		if revoked, err := revokedTokenRepo.Exists(claims.ID); err == nil && revoked {
+			cache.Add(claims.ID)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "访问令牌已失效"})
+			c.Abort()
+			return
+		}
+		if session, err := sessionRepo.FindByID(claims.SessionID); err == nil && session.RevokedAt != nil {
+			cache.Add(claims.ID)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "访问令牌已失效"})
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", claims.UserID)
+		c.Set("sessionID", claims.SessionID)
+		c.Set("jti", claims.ID)
+		c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Attachment is an uploaded blob, optionally bound to a Task. When TaskID
+// is nil the attachment is either newly uploaded and not yet attached, or
+// in use as a user's avatar (see User.Avatar) — either way, the GC job
+// that cleans expired EmailCodes also sweeps attachments that are neither.
+type Attachment struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;index;not null" json:"user_id"`
+	TaskID     *uuid.UUID `gorm:"type:uuid;index" json:"task_id"`
+	Filename   string     `gorm:"size:500;not null" json:"filename"`
+	MimeType   string     `gorm:"size:200;not null" json:"mime_type"`
+	Size       int64      `gorm:"not null" json:"size"`
+	StorageKey string     `gorm:"size:500;not null" json:"-"`
+	Checksum   string     `gorm:"size:64;not null" json:"checksum"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (a *Attachment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Session represents one logged-in device/client and doubles as a
+// refresh-token family: RefreshTokenHash always holds the hash of the
+// single currently-valid refresh token, replaced in place on every
+// rotation so presenting an already-rotated token can be recognized as
+// reuse (see service.AuthService.Refresh).
+type Session struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID           uuid.UUID  `gorm:"type:uuid;index;not null" json:"user_id"`
+	RefreshTokenHash string     `gorm:"size:64;not null" json:"-"`
+	UserAgent        string     `gorm:"size:500" json:"user_agent"`
+	IP               string     `gorm:"size:64" json:"ip"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastUsedAt       time.Time  `json:"last_used_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+}
+
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// RevokedToken is a denylist entry for an access token's jti, checked by
+// middleware.JWTAuth so a logged-out access token stops
+// working immediately instead of lingering until it expires on its own.
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;size:32" json:"-"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"-"`
+	CreatedAt time.Time `json:"-"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"snail-server/pkg/search"
+)
+
+type Task struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	ListID      uuid.UUID      `gorm:"type:uuid;index;not null" json:"list_id"`
+	UserID      uuid.UUID      `gorm:"type:uuid;index;not null" json:"user_id"`
+	Title       string         `gorm:"size:500;not null" json:"title"`
+	Content     string         `gorm:"type:text" json:"content"`
+	Priority    int            `gorm:"default:0" json:"priority"`
+	DueAt       *time.Time     `json:"due_at"`
+	CompletedAt *time.Time     `json:"completed_at"`
+	Tags        pq.StringArray `gorm:"type:text[]" json:"tags"`
+	SortOrder   int            `gorm:"default:0" json:"sort_order"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+func (t *Task) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// AfterCreate and AfterUpdate mirror the task into the search index so
+// GET /api/v1/search stays in sync without the service layer having to
+// remember to call out to pkg/search on every write path.
+func (t *Task) AfterCreate(tx *gorm.DB) error {
+	search.IndexTask(t.toDocument())
+	return nil
+}
+
+func (t *Task) AfterUpdate(tx *gorm.DB) error {
+	search.IndexTask(t.toDocument())
+	return nil
+}
+
+func (t *Task) AfterDelete(tx *gorm.DB) error {
+	search.DeleteTask(t.ID.String())
+	return nil
+}
+
+func (t *Task) toDocument() search.TaskDocument {
+	return search.TaskDocument{
+		ID:          t.ID.String(),
+		ListID:      t.ListID.String(),
+		UserID:      t.UserID.String(),
+		Title:       t.Title,
+		Content:     t.Content,
+		Tags:        []string(t.Tags),
+		Priority:    t.Priority,
+		DueAt:       t.DueAt,
+		CompletedAt: t.CompletedAt,
+	}
+}
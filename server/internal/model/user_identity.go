@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserIdentity links a local User to an identity on an external provider
+// (Google, GitHub, WeChat, or a generic OIDC issuer), so one user can bind
+// multiple providers. AccessToken/RefreshToken/ExpiresAt cache the
+// provider's own tokens for later calls against its API on the user's
+// behalf (e.g. revoking the grant on unlink); they are not used to
+// authenticate to this service.
+type UserIdentity struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;index;not null" json:"user_id"`
+	Provider       string     `gorm:"size:50;not null;uniqueIndex:idx_identity_provider_account" json:"provider"`
+	ProviderUserID string     `gorm:"size:200;not null;uniqueIndex:idx_identity_provider_account" json:"provider_user_id"`
+	Email          string     `gorm:"size:200" json:"email"`
+	AccessToken    string     `gorm:"size:2000" json:"-"`
+	RefreshToken   string     `gorm:"size:2000" json:"-"`
+	ExpiresAt      *time.Time `json:"-"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func (i *UserIdentity) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebAuthnCredential is a passkey/authenticator bound to a User, as
+// registered through the WebAuthn attestation ceremony.
+type WebAuthnCredential struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID       uuid.UUID `gorm:"type:uuid;index;not null" json:"user_id"`
+	CredentialID string    `gorm:"size:500;uniqueIndex;not null" json:"-"` // base64url of the raw authenticator-assigned ID
+	PublicKey    []byte    `gorm:"type:bytea;not null" json:"-"`
+	SignCount    uint32    `gorm:"not null;default:0" json:"-"`
+	AAGUID       string    `gorm:"size:100" json:"aaguid"` // base64url; raw AAGUID bytes are not valid UTF-8
+	Transports   string    `gorm:"size:200" json:"transports"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (c *WebAuthnCredential) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
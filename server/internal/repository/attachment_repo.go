@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"snail-server/internal/model"
+)
+
+type AttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) Create(attachment *model.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *AttachmentRepository) FindByID(id uuid.UUID) (*model.Attachment, error) {
+	var attachment model.Attachment
+	err := r.db.First(&attachment, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// SumSizeByUserID returns the total bytes a user currently has stored,
+// used to enforce the per-user quota.
+func (r *AttachmentRepository) SumSizeByUserID(userID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&model.Attachment{}).Where("user_id = ?", userID).
+		Select("COALESCE(SUM(size), 0)").Row().Scan(&total)
+	return total, err
+}
+
+func (r *AttachmentRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&model.Attachment{}, "id = ?", id).Error
+}
+
+// FindOrphaned returns attachments created before cutoff that are still
+// unattached to a task and not in use as a user's avatar, making them
+// eligible for garbage collection.
+func (r *AttachmentRepository) FindOrphaned(cutoff time.Time) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	err := r.db.Where(
+		"created_at < ? AND task_id IS NULL AND NOT EXISTS (SELECT 1 FROM users WHERE users.avatar = attachments.id::text)",
+		cutoff,
+	).Find(&attachments).Error
+	return attachments, err
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"snail-server/internal/model"
+)
+
+type RevokedTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRevokedTokenRepository(db *gorm.DB) *RevokedTokenRepository {
+	return &RevokedTokenRepository{db: db}
+}
+
+func (r *RevokedTokenRepository) Create(jti string, expiresAt time.Time) error {
+	return r.db.Create(&model.RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+}
+
+func (r *RevokedTokenRepository) Exists(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *RevokedTokenRepository) CleanExpired() error {
+	return r.db.Where("expires_at < ?", time.Now()).Delete(&model.RevokedToken{}).Error
+}
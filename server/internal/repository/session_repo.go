@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"snail-server/internal/model"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(session *model.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *SessionRepository) FindByID(id uuid.UUID) (*model.Session, error) {
+	var session model.Session
+	err := r.db.First(&session, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindActiveByUserID lists a user's sessions that have not been revoked,
+// for the account's "active devices" view.
+func (r *SessionRepository) FindActiveByUserID(userID uuid.UUID) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("last_used_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// Rotate replaces a session's refresh token hash after a successful
+// refresh and bumps LastUsedAt.
+func (r *SessionRepository) Rotate(id uuid.UUID, refreshTokenHash string) error {
+	return r.db.Model(&model.Session{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"refresh_token_hash": refreshTokenHash,
+		"last_used_at":       time.Now(),
+	}).Error
+}
+
+// Revoke marks a session (and therefore its whole refresh-token family)
+// as no longer usable.
+func (r *SessionRepository) Revoke(id uuid.UUID) error {
+	return r.db.Model(&model.Session{}).Where("id = ?", id).Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"snail-server/internal/model"
+)
+
+type TaskRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskRepository(db *gorm.DB) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+func (r *TaskRepository) Create(task *model.Task) error {
+	return r.db.Create(task).Error
+}
+
+func (r *TaskRepository) FindByListID(listID uuid.UUID) ([]model.Task, error) {
+	var tasks []model.Task
+	err := r.db.Where("list_id = ?", listID).Order("sort_order ASC, created_at ASC").Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *TaskRepository) FindByID(id uuid.UUID) (*model.Task, error) {
+	var task model.Task
+	err := r.db.First(&task, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *TaskRepository) Update(task *model.Task) error {
+	return r.db.Save(task).Error
+}
+
+func (r *TaskRepository) Delete(id uuid.UUID) error {
+	// Pass the id on the model so Task.AfterDelete sees which document to
+	// remove from the search index.
+	return r.db.Delete(&model.Task{ID: id}).Error
+}
+
+// SearchFallbackParams narrows an ILIKE/tsvector search the same way
+// search.Query narrows an Elasticsearch one.
+type SearchFallbackParams struct {
+	UserID    uuid.UUID
+	Text      string
+	ListID    *uuid.UUID
+	Tag       string
+	DueBefore *time.Time
+}
+
+// SearchFallback is used when Elasticsearch is not configured: it matches
+// title/content with to_tsvector full-text search, falling back further to
+// ILIKE for queries that don't tokenize well (short prefixes, CJK text).
+func (r *TaskRepository) SearchFallback(p SearchFallbackParams) ([]model.Task, error) {
+	query := r.db.Where("user_id = ?", p.UserID)
+
+	if p.Text != "" {
+		query = query.Where(
+			"to_tsvector('simple', title || ' ' || coalesce(content, '')) @@ plainto_tsquery('simple', ?) OR title ILIKE ? OR content ILIKE ?",
+			p.Text, "%"+p.Text+"%", "%"+p.Text+"%",
+		)
+	}
+	if p.ListID != nil {
+		query = query.Where("list_id = ?", *p.ListID)
+	}
+	if p.Tag != "" {
+		query = query.Where("? = ANY(tags)", p.Tag)
+	}
+	if p.DueBefore != nil {
+		query = query.Where("due_at <= ?", *p.DueBefore)
+	}
+
+	var tasks []model.Task
+	err := query.Order("created_at DESC").Find(&tasks).Error
+	return tasks, err
+}
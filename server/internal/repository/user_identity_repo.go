@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"snail-server/internal/model"
+)
+
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+func (r *UserIdentityRepository) Create(identity *model.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+func (r *UserIdentityRepository) FindByProvider(provider, providerUserID string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	err := r.db.Where("provider = ? AND provider_user_id = ?", provider, providerUserID).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *UserIdentityRepository) FindByUserID(userID uuid.UUID) ([]model.UserIdentity, error) {
+	var identities []model.UserIdentity
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error
+	return identities, err
+}
+
+func (r *UserIdentityRepository) Delete(userID uuid.UUID, provider string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.UserIdentity{}, "provider = ?", provider).Error
+}
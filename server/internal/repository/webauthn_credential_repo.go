@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"snail-server/internal/model"
+)
+
+type WebAuthnCredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewWebAuthnCredentialRepository(db *gorm.DB) *WebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepository{db: db}
+}
+
+func (r *WebAuthnCredentialRepository) Create(cred *model.WebAuthnCredential) error {
+	return r.db.Create(cred).Error
+}
+
+func (r *WebAuthnCredentialRepository) FindByUserID(userID uuid.UUID) ([]model.WebAuthnCredential, error) {
+	var creds []model.WebAuthnCredential
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&creds).Error
+	return creds, err
+}
+
+func (r *WebAuthnCredentialRepository) FindByCredentialID(credentialID string) (*model.WebAuthnCredential, error) {
+	var cred model.WebAuthnCredential
+	err := r.db.Where("credential_id = ?", credentialID).First(&cred).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+func (r *WebAuthnCredentialRepository) UpdateSignCount(id uuid.UUID, signCount uint32) error {
+	return r.db.Model(&model.WebAuthnCredential{}).Where("id = ?", id).Update("sign_count", signCount).Error
+}
+
+func (r *WebAuthnCredentialRepository) Delete(userID, id uuid.UUID) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.WebAuthnCredential{}, "id = ?", id).Error
+}
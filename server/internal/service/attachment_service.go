@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"snail-server/internal/config"
+	"snail-server/internal/model"
+	"snail-server/internal/repository"
+	"snail-server/pkg/storage"
+)
+
+type AttachmentService struct {
+	attachmentRepo *repository.AttachmentRepository
+}
+
+func NewAttachmentService(attachmentRepo *repository.AttachmentRepository) *AttachmentService {
+	return &AttachmentService{attachmentRepo: attachmentRepo}
+}
+
+type UploadAttachmentInput struct {
+	Filename string
+	MimeType string
+	Size     int64
+	Reader   io.Reader
+}
+
+// Upload validates the MIME type and per-user quota, stores the blob via
+// the configured storage.Driver, and records it as an Attachment.
+func (s *AttachmentService) Upload(ctx context.Context, userID uuid.UUID, input *UploadAttachmentInput) (*model.Attachment, error) {
+	cfg := config.AppConfig
+
+	if input.Size > cfg.AttachmentMaxSizeBytes {
+		return nil, fmt.Errorf("文件大小超过限制（最大 %d MB）", cfg.AttachmentMaxSizeBytes/1024/1024)
+	}
+	if !allowedMimeType(cfg.AttachmentAllowedMimeTypes, input.MimeType) {
+		return nil, errors.New("不支持的文件类型")
+	}
+
+	used, err := s.attachmentRepo.SumSizeByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if used+input.Size > cfg.AttachmentQuotaBytesUser {
+		return nil, errors.New("存储空间不足")
+	}
+
+	hasher := sha256.New()
+	key := storageKey(userID, input.Filename)
+	if _, err := storage.Default().Put(ctx, key, io.TeeReader(input.Reader, hasher), input.MimeType); err != nil {
+		return nil, err
+	}
+
+	attachment := &model.Attachment{
+		UserID:     userID,
+		Filename:   input.Filename,
+		MimeType:   input.MimeType,
+		Size:       input.Size,
+		StorageKey: key,
+		Checksum:   hex.EncodeToString(hasher.Sum(nil)),
+	}
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		_ = storage.Default().Delete(ctx, key)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// GetDownload returns the attachment metadata plus a URL the client can
+// fetch it from, after verifying ownership. The URL expires after
+// S3PresignExpiry for the S3 driver; the local driver's URL does not expire.
+func (s *AttachmentService) GetDownload(ctx context.Context, userID, attachmentID uuid.UUID) (*model.Attachment, string, error) {
+	attachment, err := s.attachmentRepo.FindByID(attachmentID)
+	if err != nil {
+		return nil, "", err
+	}
+	if attachment.UserID != userID {
+		return nil, "", errors.New("无权访问此附件")
+	}
+
+	url, err := storage.Default().PresignGet(ctx, attachment.StorageKey, config.AppConfig.S3PresignExpiry)
+	if err != nil {
+		return nil, "", err
+	}
+	return attachment, url, nil
+}
+
+// DeleteAttachment removes both the database record and the underlying blob.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, userID, attachmentID uuid.UUID) error {
+	attachment, err := s.attachmentRepo.FindByID(attachmentID)
+	if err != nil {
+		return err
+	}
+	if attachment.UserID != userID {
+		return errors.New("无权操作此附件")
+	}
+
+	if err := s.attachmentRepo.Delete(attachment.ID); err != nil {
+		return err
+	}
+	return storage.Default().Delete(ctx, attachment.StorageKey)
+}
+
+// CleanOrphaned deletes attachments older than maxAge that never got
+// attached to a task or set as an avatar. Intended to run periodically
+// alongside repository.EmailCodeRepository.CleanExpired.
+func (s *AttachmentService) CleanOrphaned(ctx context.Context, maxAge time.Duration) error {
+	orphaned, err := s.attachmentRepo.FindOrphaned(time.Now().Add(-maxAge))
+	if err != nil {
+		return err
+	}
+	for _, a := range orphaned {
+		if err := storage.Default().Delete(ctx, a.StorageKey); err != nil {
+			return err
+		}
+		if err := s.attachmentRepo.Delete(a.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func allowedMimeType(allowed []string, mimeType string) bool {
+	for _, m := range allowed {
+		if m == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+func storageKey(userID uuid.UUID, filename string) string {
+	return fmt.Sprintf("%s/%s-%s", userID.String(), uuid.New().String(), filename)
+}
@@ -1,30 +1,65 @@
 package service
 
 import (
+	"crypto/rand"
 	"errors"
 	"fmt"
-	"math/rand"
+	"math/big"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
+	gowebauthn "github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"snail-server/internal/model"
 	"snail-server/internal/repository"
+	"snail-server/pkg/captcha"
 	"snail-server/pkg/email"
 	"snail-server/pkg/jwt"
+	"snail-server/pkg/oauth"
+	"snail-server/pkg/webauthn"
 )
 
 type AuthService struct {
-	userRepo      *repository.UserRepository
-	emailCodeRepo *repository.EmailCodeRepository
+	userRepo         *repository.UserRepository
+	emailCodeRepo    *repository.EmailCodeRepository
+	userIdentityRepo *repository.UserIdentityRepository
+	webauthnCredRepo *repository.WebAuthnCredentialRepository
+	webauthnSessions *webauthn.SessionStore
+	sessionRepo      *repository.SessionRepository
+	revokedTokenRepo *repository.RevokedTokenRepository
 }
 
-func NewAuthService(userRepo *repository.UserRepository, emailCodeRepo *repository.EmailCodeRepository) *AuthService {
+func NewAuthService(
+	userRepo *repository.UserRepository,
+	emailCodeRepo *repository.EmailCodeRepository,
+	userIdentityRepo *repository.UserIdentityRepository,
+	webauthnCredRepo *repository.WebAuthnCredentialRepository,
+	webauthnSessions *webauthn.SessionStore,
+	sessionRepo *repository.SessionRepository,
+	revokedTokenRepo *repository.RevokedTokenRepository,
+) *AuthService {
 	return &AuthService{
-		userRepo:      userRepo,
-		emailCodeRepo: emailCodeRepo,
+		userRepo:         userRepo,
+		emailCodeRepo:    emailCodeRepo,
+		userIdentityRepo: userIdentityRepo,
+		webauthnCredRepo: webauthnCredRepo,
+		webauthnSessions: webauthnSessions,
+		sessionRepo:      sessionRepo,
+		revokedTokenRepo: revokedTokenRepo,
 	}
 }
 
+// SessionMeta identifies the device/client a login or refresh came from,
+// recorded on the model.Session so GET /user/sessions has something
+// meaningful to show the user.
+type SessionMeta struct {
+	UserAgent string
+	IP        string
+}
+
 type RegisterInput struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
@@ -37,8 +72,10 @@ type LoginInput struct {
 }
 
 type EmailCodeInput struct {
-	Email string `json:"email" binding:"required,email"`
-	Type  string `json:"type" binding:"required,oneof=login register"`
+	Email       string `json:"email" binding:"required,email"`
+	Type        string `json:"type" binding:"required,oneof=login register"`
+	CaptchaID   string `json:"captcha_id" binding:"required"`
+	CaptchaCode string `json:"captcha_code" binding:"required"`
 }
 
 type EmailLoginInput struct {
@@ -47,11 +84,13 @@ type EmailLoginInput struct {
 }
 
 type AuthResponse struct {
-	Token string      `json:"token"`
-	User  *model.User `json:"user"`
+	AccessToken  string      `json:"access_token"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    int64       `json:"expires_in"`
+	User         *model.User `json:"user"`
 }
 
-func (s *AuthService) Register(input *RegisterInput) (*AuthResponse, error) {
+func (s *AuthService) Register(input *RegisterInput, meta SessionMeta) (*AuthResponse, error) {
 	if s.userRepo.ExistsByEmail(input.Email) {
 		return nil, errors.New("邮箱已被注册")
 	}
@@ -75,15 +114,10 @@ func (s *AuthService) Register(input *RegisterInput) (*AuthResponse, error) {
 		return nil, err
 	}
 
-	token, err := jwt.GenerateToken(user.ID, user.Email)
-	if err != nil {
-		return nil, err
-	}
-
-	return &AuthResponse{Token: token, User: user}, nil
+	return s.issueAuthResponse(user, meta)
 }
 
-func (s *AuthService) Login(input *LoginInput) (*AuthResponse, error) {
+func (s *AuthService) Login(input *LoginInput, meta SessionMeta) (*AuthResponse, error) {
 	user, err := s.userRepo.FindByEmail(input.Email)
 	if err != nil {
 		return nil, errors.New("邮箱或密码错误")
@@ -93,20 +127,22 @@ func (s *AuthService) Login(input *LoginInput) (*AuthResponse, error) {
 		return nil, errors.New("邮箱或密码错误")
 	}
 
-	token, err := jwt.GenerateToken(user.ID, user.Email)
-	if err != nil {
-		return nil, err
-	}
-
-	return &AuthResponse{Token: token, User: user}, nil
+	return s.issueAuthResponse(user, meta)
 }
 
 func (s *AuthService) SendEmailCode(input *EmailCodeInput) error {
+	if !captcha.Verify(input.CaptchaID, input.CaptchaCode) {
+		return errors.New("图形验证码错误或已过期")
+	}
+
 	if input.Type == "register" && s.userRepo.ExistsByEmail(input.Email) {
 		return errors.New("邮箱已被注册")
 	}
 
-	code := generateCode()
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
 	emailCode := &model.EmailCode{
 		Email:     input.Email,
 		Code:      code,
@@ -121,7 +157,7 @@ func (s *AuthService) SendEmailCode(input *EmailCodeInput) error {
 	return email.SendVerificationCode(input.Email, code, input.Type)
 }
 
-func (s *AuthService) EmailLogin(input *EmailLoginInput) (*AuthResponse, error) {
+func (s *AuthService) EmailLogin(input *EmailLoginInput, meta SessionMeta) (*AuthResponse, error) {
 	emailCode, err := s.emailCodeRepo.FindValidCode(input.Email, input.Code, "login")
 	if err != nil {
 		return nil, errors.New("验证码无效或已过期")
@@ -140,15 +176,334 @@ func (s *AuthService) EmailLogin(input *EmailLoginInput) (*AuthResponse, error)
 		}
 	}
 
-	token, err := jwt.GenerateToken(user.ID, user.Email)
+	return s.issueAuthResponse(user, meta)
+}
+
+// OAuthLogin finds or creates the local user linked to a provider identity
+// and returns a normal session token for them. If the provider account is
+// not linked yet and it reports a verified email matching an existing
+// user, the accounts are linked automatically; an unverified (or absent)
+// email is never used to link, since anyone can put someone else's
+// address into an unverified profile field, so a fresh account is
+// provisioned instead.
+func (s *AuthService) OAuthLogin(provider string, info *oauth.UserInfo, meta SessionMeta) (*AuthResponse, error) {
+	identity, err := s.userIdentityRepo.FindByProvider(provider, info.ProviderUserID)
+	if err == nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return s.issueAuthResponse(user, meta)
+	}
+
+	var user *model.User
+	if info.EmailVerified && info.Email != "" {
+		if existing, err := s.userRepo.FindByEmail(info.Email); err == nil {
+			user = existing
+		}
+	}
+
+	if user == nil {
+		user = &model.User{
+			Email:    oauthProvisioningEmail(provider, info),
+			Nickname: info.Name,
+			Avatar:   info.AvatarURL,
+		}
+		if user.Nickname == "" {
+			user.Nickname = fmt.Sprintf("%s_%s", provider, info.ProviderUserID)
+		}
+		if err := s.userRepo.Create(user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userIdentityRepo.Create(&model.UserIdentity{
+		UserID:         user.ID,
+		Provider:       provider,
+		ProviderUserID: info.ProviderUserID,
+		Email:          info.Email,
+		AccessToken:    info.AccessToken,
+		RefreshToken:   info.RefreshToken,
+		ExpiresAt:      info.ExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.issueAuthResponse(user, meta)
+}
+
+// oauthProvisioningEmail returns the email to store on a newly-provisioned
+// user: the provider's email, but only once it is verified. model.User.Email
+// is unique and not-null, so an unverified or missing address is replaced
+// with a placeholder that can't collide with a real one or with another
+// placeholder for the same provider identity.
+func oauthProvisioningEmail(provider string, info *oauth.UserInfo) string {
+	if info.EmailVerified && info.Email != "" {
+		return info.Email
+	}
+	return fmt.Sprintf("%s:%s@oauth.invalid", provider, info.ProviderUserID)
+}
+
+// ListIdentities returns the external provider accounts linked to a user,
+// for account management.
+func (s *AuthService) ListIdentities(userID uuid.UUID) ([]model.UserIdentity, error) {
+	return s.userIdentityRepo.FindByUserID(userID)
+}
+
+// UnlinkIdentity removes a linked provider account from a user.
+func (s *AuthService) UnlinkIdentity(userID uuid.UUID, provider string) error {
+	return s.userIdentityRepo.Delete(userID, provider)
+}
+
+// BeginPasskeyRegistration starts the WebAuthn attestation ceremony for
+// binding a new passkey to an already-authenticated user, returning the
+// creation options to pass to navigator.credentials.create() along with a
+// cookie value the caller must hand back on Finish.
+func (s *AuthService) BeginPasskeyRegistration(userID uuid.UUID) (*protocol.CredentialCreation, string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	creds, err := s.webauthnCredRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	creation, sessionData, err := webauthn.Instance().BeginRegistration(&webauthnUser{user: user, credentials: creds})
+	if err != nil {
+		return nil, "", err
+	}
+
+	cookieValue, err := webauthn.NewChallengeCookie()
+	if err != nil {
+		return nil, "", err
+	}
+	s.webauthnSessions.Put(cookieValue, sessionData)
+
+	return creation, cookieValue, nil
+}
+
+// FinishPasskeyRegistration validates the authenticator's attestation
+// response and persists the resulting credential.
+func (s *AuthService) FinishPasskeyRegistration(userID uuid.UUID, cookieValue string, response *http.Request) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	creds, err := s.webauthnCredRepo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := s.webauthnSessions.Take(cookieValue)
+	if err != nil {
+		return err
+	}
+
+	credential, err := webauthn.Instance().FinishRegistration(&webauthnUser{user: user, credentials: creds}, *sessionData, response)
+	if err != nil {
+		return err
+	}
+
+	return s.webauthnCredRepo.Create(&model.WebAuthnCredential{
+		UserID:       userID,
+		CredentialID: encodeCredentialID(credential.ID),
+		PublicKey:    credential.PublicKey,
+		SignCount:    credential.Authenticator.SignCount,
+		AAGUID:       encodeCredentialID(credential.Authenticator.AAGUID),
+		Transports:   joinTransports(credential.Transport),
+	})
+}
+
+// BeginPasskeyLogin starts the WebAuthn assertion ceremony for the
+// passwordless login flow, discoverable-credential style (no username
+// required up front).
+func (s *AuthService) BeginPasskeyLogin() (*protocol.CredentialAssertion, string, error) {
+	assertion, sessionData, err := webauthn.Instance().BeginDiscoverableLogin()
+	if err != nil {
+		return nil, "", err
+	}
+
+	cookieValue, err := webauthn.NewChallengeCookie()
+	if err != nil {
+		return nil, "", err
+	}
+	s.webauthnSessions.Put(cookieValue, sessionData)
+
+	return assertion, cookieValue, nil
+}
+
+// FinishPasskeyLogin validates the authenticator's assertion response and
+// logs the matching user in, returning the same AuthResponse as password
+// login.
+func (s *AuthService) FinishPasskeyLogin(cookieValue string, response *http.Request, meta SessionMeta) (*AuthResponse, error) {
+	sessionData, err := s.webauthnSessions.Take(cookieValue)
 	if err != nil {
 		return nil, err
 	}
 
-	return &AuthResponse{Token: token, User: user}, nil
+	var matched *model.User
+	credential, err := webauthn.Instance().FinishDiscoverableLogin(
+		func(rawID, userHandle []byte) (gowebauthn.User, error) {
+			userID, err := uuid.Parse(string(userHandle))
+			if err != nil {
+				return nil, err
+			}
+			user, err := s.userRepo.FindByID(userID)
+			if err != nil {
+				return nil, err
+			}
+			creds, err := s.webauthnCredRepo.FindByUserID(userID)
+			if err != nil {
+				return nil, err
+			}
+			matched = user
+			return &webauthnUser{user: user, credentials: creds}, nil
+		},
+		*sessionData,
+		response,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if matched == nil {
+		return nil, errors.New("未找到匹配的用户")
+	}
+
+	stored, err := s.webauthnCredRepo.FindByCredentialID(encodeCredentialID(credential.ID))
+	if err == nil {
+		_ = s.webauthnCredRepo.UpdateSignCount(stored.ID, credential.Authenticator.SignCount)
+	}
+
+	return s.issueAuthResponse(matched, meta)
+}
+
+// ListPasskeys returns the passkeys bound to a user, for account management.
+func (s *AuthService) ListPasskeys(userID uuid.UUID) ([]model.WebAuthnCredential, error) {
+	return s.webauthnCredRepo.FindByUserID(userID)
 }
 
-func generateCode() string {
-	rand.Seed(time.Now().UnixNano())
-	return fmt.Sprintf("%06d", rand.Intn(1000000))
+// DeletePasskey removes a passkey bound to the given user.
+func (s *AuthService) DeletePasskey(userID, credentialRowID uuid.UUID) error {
+	return s.webauthnCredRepo.Delete(userID, credentialRowID)
+}
+
+func joinTransports(transports []protocol.AuthenticatorTransport) string {
+	parts := make([]string, 0, len(transports))
+	for _, t := range transports {
+		parts = append(parts, string(t))
+	}
+	return strings.Join(parts, ",")
+}
+
+// issueAuthResponse starts a brand new session (and refresh-token family)
+// for user and pairs it with a fresh access token.
+func (s *AuthService) issueAuthResponse(user *model.User, meta SessionMeta) (*AuthResponse, error) {
+	sessionID := uuid.New()
+	refreshToken, secret, err := jwt.NewRefreshToken(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &model.Session{
+		ID:               sessionID,
+		UserID:           user.ID,
+		RefreshTokenHash: jwt.HashRefreshSecret(secret),
+		UserAgent:        meta.UserAgent,
+		IP:               meta.IP,
+		LastUsedAt:       time.Now(),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return s.signAccessToken(user, sessionID, refreshToken)
+}
+
+// Refresh rotates the refresh token presented in refreshToken, returning a
+// new access/refresh token pair. Presenting a token whose secret no longer
+// matches the session's current one (i.e. a token from before the last
+// rotation) is treated as a sign the original token leaked: the whole
+// session is revoked rather than just rejecting the request.
+func (s *AuthService) Refresh(refreshToken string) (*AuthResponse, error) {
+	sessionID, secret, err := jwt.SplitRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		return nil, errors.New("无效的刷新令牌")
+	}
+	if session.RevokedAt != nil {
+		return nil, errors.New("该会话已失效，请重新登录")
+	}
+	if session.RefreshTokenHash != jwt.HashRefreshSecret(secret) {
+		_ = s.sessionRepo.Revoke(sessionID)
+		return nil, errors.New("检测到令牌重放，该会话已被撤销，请重新登录")
+	}
+
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	newRefreshToken, newSecret, err := jwt.NewRefreshToken(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.Rotate(sessionID, jwt.HashRefreshSecret(newSecret)); err != nil {
+		return nil, err
+	}
+
+	return s.signAccessToken(user, sessionID, newRefreshToken)
+}
+
+// Logout revokes the access token identified by jti (so it is rejected
+// immediately rather than lingering until it expires) and the session it
+// belongs to (so its refresh token can no longer be used either).
+func (s *AuthService) Logout(sessionID uuid.UUID, jti string, accessTokenExpiresAt time.Time) error {
+	if err := s.revokedTokenRepo.Create(jti, accessTokenExpiresAt); err != nil {
+		return err
+	}
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+// ListSessions returns a user's active (non-revoked) devices/sessions.
+func (s *AuthService) ListSessions(userID uuid.UUID) ([]model.Session, error) {
+	return s.sessionRepo.FindActiveByUserID(userID)
+}
+
+// RevokeSession lets a user kill one of their own sessions remotely (e.g.
+// "log out this device"), requiring ownership.
+func (s *AuthService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return errors.New("无权操作此会话")
+	}
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+func (s *AuthService) signAccessToken(user *model.User, sessionID uuid.UUID, refreshToken string) (*AuthResponse, error) {
+	accessToken, _, expiresAt, err := jwt.GenerateAccessToken(user.ID, user.Email, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(time.Until(expiresAt).Seconds()),
+		User:         user,
+	}, nil
+}
+
+func generateCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"snail-server/internal/model"
 	"snail-server/internal/repository"
+	"snail-server/pkg/realtime"
 )
 
 type ListService struct {
@@ -47,6 +48,8 @@ func (s *ListService) CreateList(userID uuid.UUID, input *CreateListInput) (*mod
 		return nil, err
 	}
 
+	realtime.Publish(userID, "list.created", list)
+
 	return list, nil
 }
 
@@ -77,6 +80,8 @@ func (s *ListService) UpdateList(userID, listID uuid.UUID, input *UpdateListInpu
 		return nil, err
 	}
 
+	realtime.Publish(userID, "list.updated", list)
+
 	return list, nil
 }
 
@@ -90,5 +95,11 @@ func (s *ListService) DeleteList(userID, listID uuid.UUID) error {
 		return errors.New("无权操作此清单")
 	}
 
-	return s.listRepo.Delete(listID)
+	if err := s.listRepo.Delete(listID); err != nil {
+		return err
+	}
+
+	realtime.Publish(userID, "list.deleted", map[string]uuid.UUID{"id": listID})
+
+	return nil
 }
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"snail-server/internal/model"
+	"snail-server/internal/repository"
+	"snail-server/pkg/realtime"
+	"snail-server/pkg/search"
+)
+
+type TaskService struct {
+	taskRepo *repository.TaskRepository
+	listRepo *repository.ListRepository
+}
+
+func NewTaskService(taskRepo *repository.TaskRepository, listRepo *repository.ListRepository) *TaskService {
+	return &TaskService{taskRepo: taskRepo, listRepo: listRepo}
+}
+
+type CreateTaskInput struct {
+	Title     string     `json:"title" binding:"required"`
+	Content   string     `json:"content"`
+	Priority  int        `json:"priority"`
+	DueAt     *time.Time `json:"due_at"`
+	Tags      []string   `json:"tags"`
+	SortOrder int        `json:"sort_order"`
+}
+
+type UpdateTaskInput struct {
+	Title       *string    `json:"title"`
+	Content     *string    `json:"content"`
+	Priority    *int       `json:"priority"`
+	DueAt       *time.Time `json:"due_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	Tags        []string   `json:"tags"`
+	SortOrder   *int       `json:"sort_order"`
+}
+
+type SearchTasksInput struct {
+	Query     string
+	ListID    *uuid.UUID
+	Tag       string
+	DueBefore *time.Time
+}
+
+func (s *TaskService) GetTasks(userID, listID uuid.UUID) ([]model.Task, error) {
+	list, err := s.listRepo.FindByID(listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != userID {
+		return nil, errors.New("无权操作此清单")
+	}
+
+	return s.taskRepo.FindByListID(listID)
+}
+
+func (s *TaskService) CreateTask(userID, listID uuid.UUID, input *CreateTaskInput) (*model.Task, error) {
+	list, err := s.listRepo.FindByID(listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != userID {
+		return nil, errors.New("无权操作此清单")
+	}
+
+	task := &model.Task{
+		ListID:    listID,
+		UserID:    userID,
+		Title:     input.Title,
+		Content:   input.Content,
+		Priority:  input.Priority,
+		DueAt:     input.DueAt,
+		Tags:      input.Tags,
+		SortOrder: input.SortOrder,
+	}
+
+	if err := s.taskRepo.Create(task); err != nil {
+		return nil, err
+	}
+
+	realtime.Publish(userID, "task.created", task)
+
+	return task, nil
+}
+
+func (s *TaskService) UpdateTask(userID, taskID uuid.UUID, input *UpdateTaskInput) (*model.Task, error) {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.UserID != userID {
+		return nil, errors.New("无权操作此任务")
+	}
+
+	if input.Title != nil {
+		task.Title = *input.Title
+	}
+	if input.Content != nil {
+		task.Content = *input.Content
+	}
+	if input.Priority != nil {
+		task.Priority = *input.Priority
+	}
+	if input.DueAt != nil {
+		task.DueAt = input.DueAt
+	}
+	if input.CompletedAt != nil {
+		task.CompletedAt = input.CompletedAt
+	}
+	if input.Tags != nil {
+		task.Tags = input.Tags
+	}
+	if input.SortOrder != nil {
+		task.SortOrder = *input.SortOrder
+	}
+
+	if err := s.taskRepo.Update(task); err != nil {
+		return nil, err
+	}
+
+	realtime.Publish(userID, "task.updated", task)
+
+	return task, nil
+}
+
+func (s *TaskService) DeleteTask(userID, taskID uuid.UUID) error {
+	task, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		return err
+	}
+
+	if task.UserID != userID {
+		return errors.New("无权操作此任务")
+	}
+
+	if err := s.taskRepo.Delete(taskID); err != nil {
+		return err
+	}
+
+	realtime.Publish(userID, "task.deleted", map[string]uuid.UUID{"id": taskID})
+
+	return nil
+}
+
+// Search queries Elasticsearch when it is configured, and transparently
+// falls back to a Postgres ILIKE/tsvector search otherwise so the feature
+// still works in dev environments without an ES cluster.
+func (s *TaskService) Search(ctx context.Context, userID uuid.UUID, input *SearchTasksInput) ([]search.SearchHit, error) {
+	if search.Enabled() {
+		query := search.Query{
+			UserID:    userID.String(),
+			Text:      input.Query,
+			Tag:       input.Tag,
+			DueBefore: input.DueBefore,
+		}
+		if input.ListID != nil {
+			query.ListID = input.ListID.String()
+		}
+		return search.Search(ctx, query)
+	}
+
+	tasks, err := s.taskRepo.SearchFallback(repository.SearchFallbackParams{
+		UserID:    userID,
+		Text:      input.Query,
+		ListID:    input.ListID,
+		Tag:       input.Tag,
+		DueBefore: input.DueBefore,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]search.SearchHit, 0, len(tasks))
+	for _, t := range tasks {
+		hits = append(hits, search.SearchHit{
+			Task: search.TaskDocument{
+				ID:          t.ID.String(),
+				ListID:      t.ListID.String(),
+				UserID:      t.UserID.String(),
+				Title:       t.Title,
+				Content:     t.Content,
+				Tags:        []string(t.Tags),
+				Priority:    t.Priority,
+				DueAt:       t.DueAt,
+				CompletedAt: t.CompletedAt,
+			},
+		})
+	}
+	return hits, nil
+}
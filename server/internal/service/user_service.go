@@ -10,16 +10,21 @@ import (
 )
 
 type UserService struct {
-	userRepo *repository.UserRepository
+	userRepo       *repository.UserRepository
+	attachmentRepo *repository.AttachmentRepository
 }
 
-func NewUserService(userRepo *repository.UserRepository) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(userRepo *repository.UserRepository, attachmentRepo *repository.AttachmentRepository) *UserService {
+	return &UserService{userRepo: userRepo, attachmentRepo: attachmentRepo}
 }
 
 type UpdateUserInput struct {
 	Nickname string `json:"nickname"`
-	Avatar   string `json:"avatar"`
+	// Avatar accepts either a plain URL (stored as-is, e.g. from OAuth
+	// profile sync) or the id of an attachment the caller previously
+	// uploaded via POST /attachments, stored as-is too; the client
+	// resolves the latter to a downloadable URL via GET /attachments/:id.
+	Avatar string `json:"avatar"`
 }
 
 type UpdatePasswordInput struct {
@@ -41,6 +46,9 @@ func (s *UserService) UpdateUser(id uuid.UUID, input *UpdateUserInput) (*model.U
 		user.Nickname = input.Nickname
 	}
 	if input.Avatar != "" {
+		if err := s.validateAvatar(id, input.Avatar); err != nil {
+			return nil, err
+		}
 		user.Avatar = input.Avatar
 	}
 
@@ -51,6 +59,24 @@ func (s *UserService) UpdateUser(id uuid.UUID, input *UpdateUserInput) (*model.U
 	return user, nil
 }
 
+// validateAvatar checks ownership when avatar refers to an attachment id;
+// plain URLs (e.g. synced from an OAuth profile) pass through untouched.
+func (s *UserService) validateAvatar(userID uuid.UUID, avatar string) error {
+	attachmentID, err := uuid.Parse(avatar)
+	if err != nil {
+		return nil
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(attachmentID)
+	if err != nil {
+		return errors.New("头像附件不存在")
+	}
+	if attachment.UserID != userID {
+		return errors.New("无权使用该附件作为头像")
+	}
+	return nil
+}
+
 func (s *UserService) UpdatePassword(id uuid.UUID, input *UpdatePasswordInput) error {
 	user, err := s.userRepo.FindByID(id)
 	if err != nil {
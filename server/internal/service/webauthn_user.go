@@ -0,0 +1,87 @@
+package service
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	gowebauthn "github.com/go-webauthn/webauthn/webauthn"
+	"snail-server/internal/model"
+)
+
+// encodeCredentialID converts a raw WebAuthn credential ID (arbitrary,
+// often non-UTF-8 bytes) into the base64url text model.WebAuthnCredential
+// stores it as, so it can live in a varchar column.
+func encodeCredentialID(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+// decodeCredentialID reverses encodeCredentialID, recovering the raw bytes
+// the go-webauthn library expects to compare against an assertion's rawID.
+func decodeCredentialID(encoded string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(encoded)
+}
+
+// decodeAAGUID reverses the same encoding applied to AAGUID on store;
+// a decode failure just yields an empty AAGUID rather than aborting login.
+func decodeAAGUID(encoded string) []byte {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+// webauthnUser adapts a model.User plus its bound credentials to the
+// github.com/go-webauthn/webauthn.User interface.
+type webauthnUser struct {
+	user        *model.User
+	credentials []model.WebAuthnCredential
+}
+
+func (u *webauthnUser) WebAuthnID() []byte {
+	return []byte(u.user.ID.String())
+}
+
+func (u *webauthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	if u.user.Nickname != "" {
+		return u.user.Nickname
+	}
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnCredentials() []gowebauthn.Credential {
+	out := make([]gowebauthn.Credential, 0, len(u.credentials))
+	for _, c := range u.credentials {
+		rawID, err := decodeCredentialID(c.CredentialID)
+		if err != nil {
+			continue
+		}
+		out = append(out, gowebauthn.Credential{
+			ID:        rawID,
+			PublicKey: c.PublicKey,
+			Transport: parseTransports(c.Transports),
+			Authenticator: gowebauthn.Authenticator{
+				AAGUID:    decodeAAGUID(c.AAGUID),
+				SignCount: c.SignCount,
+			},
+		})
+	}
+	return out
+}
+
+func parseTransports(raw string) []protocol.AuthenticatorTransport {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	out := make([]protocol.AuthenticatorTransport, 0, len(parts))
+	for _, p := range parts {
+		out = append(out, protocol.AuthenticatorTransport(p))
+	}
+	return out
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -13,6 +15,12 @@ import (
 	"snail-server/pkg/database"
 	"snail-server/pkg/email"
 	"snail-server/pkg/jwt"
+	"snail-server/pkg/oauth"
+	"snail-server/pkg/ratelimit"
+	"snail-server/pkg/realtime"
+	"snail-server/pkg/search"
+	"snail-server/pkg/storage"
+	"snail-server/pkg/webauthn"
 )
 
 func main() {
@@ -20,7 +28,7 @@ func main() {
 	config.Load()
 
 	// 初始化 JWT
-	jwt.Init(config.AppConfig.JWTSecret, config.AppConfig.JWTExpireHours)
+	jwt.Init(config.AppConfig.JWTSecret)
 
 	// 初始化邮件
 	email.Init(&email.SMTPConfig{
@@ -31,6 +39,36 @@ func main() {
 		From:     config.AppConfig.SMTPFrom,
 	})
 
+	// 注册第三方登录 provider
+	registerOAuthProviders()
+
+	// 初始化 WebAuthn
+	if err := webauthn.Init(config.AppConfig.WebAuthnRPID, config.AppConfig.WebAuthnRPDisplayName, config.AppConfig.WebAuthnRPOrigins); err != nil {
+		log.Fatal("Failed to init webauthn:", err)
+	}
+
+	// 初始化搜索索引（未配置时自动降级为 Postgres 搜索）
+	if err := search.Init(config.AppConfig.ElasticsearchAddresses, config.AppConfig.ElasticsearchIndex); err != nil {
+		log.Fatal("Failed to init search:", err)
+	}
+
+	// 初始化实时同步 hub（进程内 pub/sub，后续可替换为 Redis）
+	realtime.Init(nil)
+
+	// 初始化附件存储
+	if err := storage.Init(config.AppConfig.StorageDriver, storage.Config{
+		LocalBaseDir:      config.AppConfig.LocalStorageDir,
+		LocalBaseURL:      config.AppConfig.LocalStorageBaseURL,
+		S3Endpoint:        config.AppConfig.S3Endpoint,
+		S3Region:          config.AppConfig.S3Region,
+		S3Bucket:          config.AppConfig.S3Bucket,
+		S3AccessKeyID:     config.AppConfig.S3AccessKeyID,
+		S3SecretAccessKey: config.AppConfig.S3SecretAccessKey,
+		S3UseSSL:          config.AppConfig.S3UseSSL,
+	}); err != nil {
+		log.Fatal("Failed to init storage:", err)
+	}
+
 	// 连接数据库
 	if err := database.Connect(config.AppConfig.DatabaseURL); err != nil {
 		log.Fatal("Failed to connect database:", err)
@@ -45,16 +83,33 @@ func main() {
 	userRepo := repository.NewUserRepository(database.DB)
 	emailCodeRepo := repository.NewEmailCodeRepository(database.DB)
 	listRepo := repository.NewListRepository(database.DB)
+	userIdentityRepo := repository.NewUserIdentityRepository(database.DB)
+	webauthnCredRepo := repository.NewWebAuthnCredentialRepository(database.DB)
+	taskRepo := repository.NewTaskRepository(database.DB)
+	attachmentRepo := repository.NewAttachmentRepository(database.DB)
+	sessionRepo := repository.NewSessionRepository(database.DB)
+	revokedTokenRepo := repository.NewRevokedTokenRepository(database.DB)
 
 	// 初始化 services
-	authService := service.NewAuthService(userRepo, emailCodeRepo)
-	userService := service.NewUserService(userRepo)
+	authService := service.NewAuthService(userRepo, emailCodeRepo, userIdentityRepo, webauthnCredRepo, webauthn.NewSessionStore(), sessionRepo, revokedTokenRepo)
+	userService := service.NewUserService(userRepo, attachmentRepo)
 	listService := service.NewListService(listRepo)
+	taskService := service.NewTaskService(taskRepo, listRepo)
+	attachmentService := service.NewAttachmentService(attachmentRepo)
 
 	// 初始化 handlers
 	authHandler := handler.NewAuthHandler(authService)
-	userHandler := handler.NewUserHandler(userService)
+	userHandler := handler.NewUserHandler(userService, authService)
 	listHandler := handler.NewListHandler(listService)
+	oauthHandler := handler.NewOAuthHandler(authService, oauth.NewStateStore())
+	webauthnHandler := handler.NewWebAuthnHandler(authService)
+	taskHandler := handler.NewTaskHandler(taskService)
+	searchHandler := handler.NewSearchHandler(taskService)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+	wsHandler := handler.NewWSHandler(revokedTokenRepo)
+
+	// 定期清理过期邮箱验证码、孤立附件和过期的已撤销令牌记录
+	go runCleanupJob(emailCodeRepo, attachmentService, revokedTokenRepo)
 
 	r := gin.Default()
 	r.Use(middleware.CORS())
@@ -62,32 +117,78 @@ func main() {
 	// 健康检查
 	r.GET("/health", handler.Health)
 
+	emailCodeRateLimit := middleware.RateLimit(ratelimit.NewMemoryStore(),
+		middleware.RateLimitRule{Name: "email_code:email_minute", KeyFunc: middleware.ByJSONField("email"), Capacity: 5, Window: time.Minute},
+		middleware.RateLimitRule{Name: "email_code:ip_minute", KeyFunc: middleware.ByClientIP, Capacity: 20, Window: time.Minute},
+		middleware.RateLimitRule{Name: "email_code:email_day", KeyFunc: middleware.ByJSONField("email"), Capacity: 100, Window: 24 * time.Hour},
+	)
+
 	// API 路由
 	api := r.Group("/api/v1")
 	{
+		api.GET("/captcha", handler.Captcha)
+
 		// 公开路由
 		auth := api.Group("/auth")
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
-			auth.POST("/email/code", authHandler.SendEmailCode)
+			auth.POST("/email/code", emailCodeRateLimit, authHandler.SendEmailCode)
 			auth.POST("/email/login", authHandler.EmailLogin)
+			auth.POST("/refresh", authHandler.Refresh)
+
+			oauthGroup := auth.Group("/oauth/:provider")
+			{
+				oauthGroup.GET("/login", oauthHandler.Login)
+				oauthGroup.GET("/callback", oauthHandler.Callback)
+			}
+
+			auth.POST("/webauthn/login/begin", webauthnHandler.BeginLogin)
+			auth.POST("/webauthn/login/finish", webauthnHandler.FinishLogin)
 		}
 
+		// WebSocket 实时同步（token 通过 query 参数或 Sec-WebSocket-Protocol 传递，
+		// 因此不走基于 Header 的 JWTAuth 中间件，认证在 handler 内部完成）
+		api.GET("/ws", wsHandler.WS)
+
 		// 需要认证的路由
 		protected := api.Group("")
-		protected.Use(middleware.JWTAuth())
+		protected.Use(middleware.JWTAuth(revokedTokenRepo, sessionRepo))
 		{
 			// 用户
 			protected.GET("/user/profile", userHandler.GetProfile)
 			protected.PUT("/user/profile", userHandler.UpdateProfile)
 			protected.PUT("/user/password", userHandler.UpdatePassword)
+			protected.GET("/user/passkeys", userHandler.GetPasskeys)
+			protected.DELETE("/user/passkeys/:id", userHandler.DeletePasskey)
+			protected.GET("/user/sessions", userHandler.GetSessions)
+			protected.DELETE("/user/sessions/:id", userHandler.DeleteSession)
+			protected.GET("/user/identities", userHandler.GetIdentities)
+			protected.DELETE("/user/identities/:provider", userHandler.DeleteIdentity)
+
+			protected.POST("/auth/logout", authHandler.Logout)
+			protected.POST("/auth/webauthn/register/begin", webauthnHandler.BeginRegister)
+			protected.POST("/auth/webauthn/register/finish", webauthnHandler.FinishRegister)
 
 			// 清单
 			protected.GET("/lists", listHandler.GetLists)
 			protected.POST("/lists", listHandler.CreateList)
 			protected.PUT("/lists/:id", listHandler.UpdateList)
 			protected.DELETE("/lists/:id", listHandler.DeleteList)
+
+			// 任务
+			protected.GET("/lists/:id/tasks", taskHandler.GetTasks)
+			protected.POST("/lists/:id/tasks", taskHandler.CreateTask)
+			protected.PUT("/tasks/:id", taskHandler.UpdateTask)
+			protected.DELETE("/tasks/:id", taskHandler.DeleteTask)
+
+			// 搜索
+			protected.GET("/search", searchHandler.Search)
+
+			// 附件
+			protected.POST("/attachments", attachmentHandler.Upload)
+			protected.GET("/attachments/:id", attachmentHandler.Get)
+			protected.DELETE("/attachments/:id", attachmentHandler.Delete)
 		}
 	}
 
@@ -96,3 +197,70 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// registerOAuthProviders wires up every social login provider that has
+// credentials configured in the environment. A provider with an empty
+// client ID is skipped rather than registered half-configured.
+func registerOAuthProviders() {
+	cfg := config.AppConfig
+	redirect := func(provider string) string {
+		return cfg.OAuthRedirectBaseURL + "/api/v1/auth/oauth/" + provider + "/callback"
+	}
+
+	if cfg.GoogleClientID != "" {
+		oauth.Register(&oauth.GoogleProvider{
+			ClientID:     cfg.GoogleClientID,
+			ClientSecret: cfg.GoogleClientSecret,
+			RedirectURL:  redirect("google"),
+		})
+	}
+	if cfg.GitHubClientID != "" {
+		oauth.Register(&oauth.GitHubProvider{
+			ClientID:     cfg.GitHubClientID,
+			ClientSecret: cfg.GitHubClientSecret,
+			RedirectURL:  redirect("github"),
+		})
+	}
+	if cfg.WechatAppID != "" {
+		oauth.Register(&oauth.WechatProvider{
+			AppID:     cfg.WechatAppID,
+			AppSecret: cfg.WechatAppSecret,
+		})
+	}
+	if cfg.OIDCClientID != "" {
+		oauth.Register(&oauth.GenericOIDCProvider{
+			IssuerURL:    cfg.OIDCIssuerURL,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  redirect("oidc"),
+		})
+	}
+}
+
+// cleanupInterval is how often expired email codes and orphaned attachments
+// are swept; attachmentOrphanAge is how long an unattached, non-avatar
+// attachment is kept before being considered abandoned.
+const (
+	cleanupInterval     = time.Hour
+	attachmentOrphanAge = 24 * time.Hour
+)
+
+// runCleanupJob periodically removes expired email verification codes and
+// garbage-collects attachments that were uploaded but never attached to a
+// task or set as an avatar. It runs for the lifetime of the process.
+func runCleanupJob(emailCodeRepo *repository.EmailCodeRepository, attachmentService *service.AttachmentService, revokedTokenRepo *repository.RevokedTokenRepository) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := emailCodeRepo.CleanExpired(); err != nil {
+			log.Println("Failed to clean expired email codes:", err)
+		}
+		if err := attachmentService.CleanOrphaned(context.Background(), attachmentOrphanAge); err != nil {
+			log.Println("Failed to clean orphaned attachments:", err)
+		}
+		if err := revokedTokenRepo.CleanExpired(); err != nil {
+			log.Println("Failed to clean expired revoked tokens:", err)
+		}
+	}
+}
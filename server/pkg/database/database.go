@@ -29,5 +29,11 @@ func AutoMigrate() error {
 		&model.User{},
 		&model.EmailCode{},
 		&model.List{},
+		&model.UserIdentity{},
+		&model.WebAuthnCredential{},
+		&model.Task{},
+		&model.Attachment{},
+		&model.Session{},
+		&model.RevokedToken{},
 	)
 }
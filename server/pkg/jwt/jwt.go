@@ -0,0 +1,135 @@
+// Package jwt issues and validates the short-lived JWT access tokens used
+// to authenticate API requests, plus the opaque refresh tokens that renew
+// them. See service.AuthService for the session/rotation logic that sits
+// on top of these primitives.
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// AccessTokenTTL and RefreshTokenTTL are fixed rather than configurable:
+// short-lived access tokens bound the blast radius of a leaked token,
+// while the refresh token's lifetime is long enough that a user isn't
+// forced to log in again on every visit.
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var secretKey []byte
+
+// Init configures the signing key used for every access token issued
+// afterward. Must be called once at startup before GenerateAccessToken or
+// ParseToken are used.
+func Init(secret string) {
+	secretKey = []byte(secret)
+}
+
+// Claims is the access token payload. ID (the registered "jti" claim)
+// identifies this specific token so it can be revoked independently of
+// its session; SessionID ties it back to the model.Session (and thus
+// refresh-token family) it was issued alongside, so logout can revoke
+// both together.
+type Claims struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	SessionID uuid.UUID `json:"session_id"`
+	jwt.RegisteredClaims
+}
+
+// GenerateAccessToken issues a signed access token for userID within
+// sessionID, returning its jti alongside so callers can track it for
+// revocation.
+func GenerateAccessToken(userID uuid.UUID, email string, sessionID uuid.UUID) (token, jti string, expiresAt time.Time, err error) {
+	jti, err = randomURLSafe(16)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	expiresAt = time.Now().Add(AccessTokenTTL)
+
+	claims := Claims{
+		UserID:    userID,
+		Email:     email,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secretKey)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	return signed, jti, expiresAt, nil
+}
+
+// ParseToken validates signature and expiry and returns the token's
+// claims. It does not check revocation — callers that care about
+// revoked/logged-out tokens must consult a denylist themselves (see
+// middleware.JWTAuth).
+func ParseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return secretKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("无效的访问令牌")
+	}
+	return claims, nil
+}
+
+// NewRefreshToken creates an opaque refresh token for sessionID. The
+// returned token embeds the session id so a presented token can be
+// looked up without scanning every session's hash; only HashRefreshSecret's
+// output is ever persisted.
+func NewRefreshToken(sessionID uuid.UUID) (token, secret string, err error) {
+	secret, err = randomURLSafe(32)
+	if err != nil {
+		return "", "", err
+	}
+	return sessionID.String() + "." + secret, secret, nil
+}
+
+// SplitRefreshToken recovers the session id and secret embedded by
+// NewRefreshToken.
+func SplitRefreshToken(token string) (sessionID uuid.UUID, secret string, err error) {
+	idPart, secret, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, "", errors.New("无效的刷新令牌")
+	}
+	sessionID, err = uuid.Parse(idPart)
+	if err != nil {
+		return uuid.Nil, "", errors.New("无效的刷新令牌")
+	}
+	return sessionID, secret, nil
+}
+
+// HashRefreshSecret returns the value stored in model.Session so the raw
+// secret never touches the database.
+func HashRefreshSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomURLSafe(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
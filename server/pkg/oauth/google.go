@@ -0,0 +1,91 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	googleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleUserInfoURL = "https://www.googleapis.com/oauth2/v3/userinfo"
+)
+
+// GoogleProvider implements Provider for Google's OAuth2/OIDC endpoints.
+type GoogleProvider struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+func (p *GoogleProvider) Name() string { return "google" }
+
+func (p *GoogleProvider) AuthURL(state, codeChallenge string) string {
+	q := url.Values{
+		"client_id":             {p.ClientID},
+		"redirect_uri":          {p.RedirectURL},
+		"response_type":         {"code"},
+		"scope":                 {"openid email profile"},
+		"state":                 {state},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	return googleAuthURL + "?" + q.Encode()
+}
+
+func (p *GoogleProvider) Exchange(ctx context.Context, code, codeVerifier string) (*UserInfo, error) {
+	token, err := exchangeAuthorizationCode(ctx, googleTokenURL, url.Values{
+		"client_id":     {p.ClientID},
+		"client_secret": {p.ClientSecret},
+		"code":          {code},
+		"code_verifier": {codeVerifier},
+		"redirect_uri":  {p.RedirectURL},
+		"grant_type":    {"authorization_code"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, googleUserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("google userinfo failed: %s: %s", resp.Status, body)
+	}
+
+	var profile struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		Picture       string `json:"picture"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, err
+	}
+
+	return &UserInfo{
+		ProviderUserID: profile.Sub,
+		Email:          profile.Email,
+		EmailVerified:  profile.EmailVerified,
+		Name:           profile.Name,
+		AvatarURL:      profile.Picture,
+		AccessToken:    token.AccessToken,
+		RefreshToken:   token.RefreshToken,
+		ExpiresAt:      token.expiresAt(),
+	}, nil
+}
@@ -0,0 +1,57 @@
+// Package oauth implements the authorization-code + PKCE flow against a
+// small set of external identity providers and exposes them behind a
+// common Provider interface so the handler layer does not need to know
+// which one it is talking to.
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// UserInfo is the normalized profile returned by a provider after the
+// code exchange, regardless of how that provider shapes its own response.
+// EmailVerified reports whether the provider itself attests the address
+// was verified; callers must not use an unverified Email to link to an
+// existing local account, since anyone can put someone else's address in
+// an unverified profile field.
+type UserInfo struct {
+	ProviderUserID string
+	Email          string
+	EmailVerified  bool
+	Name           string
+	AvatarURL      string
+
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    *time.Time
+}
+
+// Provider is implemented by every supported identity provider.
+type Provider interface {
+	// Name returns the provider key used in routes and the user_identities table.
+	Name() string
+	// AuthURL builds the redirect target for the authorization request.
+	AuthURL(state, codeChallenge string) string
+	// Exchange trades an authorization code (plus the PKCE verifier) for
+	// an access token and fetches the authenticated user's profile.
+	Exchange(ctx context.Context, code, codeVerifier string) (*UserInfo, error)
+}
+
+var registry = map[string]Provider{}
+
+// Register adds a provider to the registry. Called from config wiring at
+// startup; panics on duplicate registration since that is a programmer error.
+func Register(p Provider) {
+	if _, exists := registry[p.Name()]; exists {
+		panic(fmt.Sprintf("oauth: provider %q already registered", p.Name()))
+	}
+	registry[p.Name()] = p
+}
+
+// Get looks up a registered provider by name.
+func Get(name string) (Provider, bool) {
+	p, ok := registry[name]
+	return p, ok
+}
@@ -0,0 +1,31 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// NewState generates a random, URL-safe state token.
+func NewState() (string, error) {
+	return randomURLSafe(32)
+}
+
+// NewCodeVerifier generates a PKCE code_verifier per RFC 7636.
+func NewCodeVerifier() (string, error) {
+	return randomURLSafe(64)
+}
+
+// CodeChallengeS256 derives the S256 code_challenge for a code_verifier.
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomURLSafe(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,83 @@
+package oauth
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// stateTTL is how long a cookie-keyed state/verifier pair stays valid;
+// the login and callback requests normally land a few seconds apart.
+const stateTTL = 10 * time.Minute
+
+// pendingLogin is what gets stashed server-side between the redirect to
+// the provider and the callback, keyed by a cookie set on the browser.
+type pendingLogin struct {
+	Provider     string
+	State        string
+	CodeVerifier string
+	expiresAt    time.Time
+}
+
+// StateStore is an in-memory, TTL'd map from cookie value to the
+// in-flight login it belongs to. A single process is enough here because
+// the cookie never needs to be valid across instances longer than the
+// login round-trip.
+type StateStore struct {
+	mu      sync.Mutex
+	entries map[string]pendingLogin
+}
+
+// NewStateStore creates an empty store and starts its background sweep.
+func NewStateStore() *StateStore {
+	s := &StateStore{entries: make(map[string]pendingLogin)}
+	go s.sweepLoop()
+	return s
+}
+
+// Put stores a pending login under cookieValue.
+func (s *StateStore) Put(cookieValue, provider, state, codeVerifier string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[cookieValue] = pendingLogin{
+		Provider:     provider,
+		State:        state,
+		CodeVerifier: codeVerifier,
+		expiresAt:    time.Now().Add(stateTTL),
+	}
+}
+
+// Take validates and removes the pending login for cookieValue, checking
+// it matches the given provider and state from the callback request.
+func (s *StateStore) Take(cookieValue, provider, state string) (codeVerifier string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[cookieValue]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(s.entries, cookieValue)
+		return "", errors.New("登录状态已过期，请重新登录")
+	}
+	delete(s.entries, cookieValue)
+
+	if entry.Provider != provider || entry.State != state {
+		return "", errors.New("state 校验失败")
+	}
+
+	return entry.CodeVerifier, nil
+}
+
+func (s *StateStore) sweepLoop() {
+	ticker := time.NewTicker(stateTTL)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		s.mu.Lock()
+		for key, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,74 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// tokenResponse is the common shape of a provider's token endpoint response.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	Scope        string `json:"scope"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// exchangeAuthorizationCode POSTs form-encoded params to a provider's
+// token endpoint and decodes the access token out of the JSON response.
+func exchangeAuthorizationCode(ctx context.Context, tokenURL string, form url.Values) (*tokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token exchange failed: %s: %s", resp.Status, body)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token exchange returned no access_token: %s", body)
+	}
+
+	return &token, nil
+}
+
+// expiresAt converts the token response's relative expires_in (seconds)
+// into an absolute time, or nil if the provider didn't send one.
+func (t *tokenResponse) expiresAt() *time.Time {
+	return expiresInToTime(t.ExpiresIn)
+}
+
+// expiresInToTime converts a relative expires_in (seconds) into an
+// absolute time, or nil if the provider didn't send one. Shared with
+// providers (e.g. WeChat) whose token response doesn't use tokenResponse.
+func expiresInToTime(expiresInSeconds int64) *time.Time {
+	if expiresInSeconds <= 0 {
+		return nil
+	}
+	at := time.Now().Add(time.Duration(expiresInSeconds) * time.Second)
+	return &at
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens     float64
+	capacity   float64
+	refillRate float64 // tokens per second
+	lastRefill time.Time
+}
+
+// MemoryStore is an in-process token-bucket Store. It is the default used
+// in dev/single-instance deployments; a Redis-backed Store is a drop-in
+// replacement for running behind multiple server instances.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryStore) Allow(key string, capacity int, window time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{
+			tokens:     float64(capacity),
+			capacity:   float64(capacity),
+			refillRate: float64(capacity) / window.Seconds(),
+			lastRefill: time.Now(),
+		}
+		s.buckets[key] = b
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.tokens += elapsed * b.refillRate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		return false, nil
+	}
+	b.tokens--
+	return true, nil
+}
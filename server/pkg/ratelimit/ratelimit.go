@@ -0,0 +1,13 @@
+// Package ratelimit implements a token-bucket limiter behind a small
+// Store interface, so the in-memory implementation used today can later
+// be swapped for a Redis-backed one (e.g. an INCR + EXPIRE Lua script)
+// without touching callers.
+package ratelimit
+
+import "time"
+
+// Store checks and consumes one token for key, where the bucket holds up
+// to capacity tokens and refills to capacity every window.
+type Store interface {
+	Allow(key string, capacity int, window time.Duration) (bool, error)
+}
@@ -0,0 +1,84 @@
+package realtime
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = (pongWait * 9) / 10
+
+	// sendBufferSize bounds how many unsent events a client can accrue
+	// before it is treated as slow and dropped.
+	sendBufferSize = 64
+)
+
+// Client is one device's WebSocket connection, subscribed to its user's
+// room on the Hub's PubSub.
+type Client struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+func newClient(conn *websocket.Conn) *Client {
+	return &Client{conn: conn, send: make(chan []byte, sendBufferSize)}
+}
+
+// enqueue hands an event to the client's write buffer. If the buffer is
+// full the client is too slow to keep up and gets disconnected instead of
+// letting the backlog grow unbounded.
+func (c *Client) enqueue(data []byte) bool {
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) readPump(onClose func()) {
+	defer onClose()
+	defer c.conn.Close()
+
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		// The client only ever sends control frames (pong); any payload
+		// message is simply discarded to keep the read loop pumping.
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	defer c.conn.Close()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// Event is the JSON shape every client receives over the socket.
+type Event struct {
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload"`
+}
+
+var hub *Hub
+
+// Init creates the package-level Hub other packages publish through.
+// Passing nil uses the default in-process PubSub; pass a Redis-backed
+// PubSub here to fan events out across multiple server instances.
+func Init(pubsub PubSub) {
+	if pubsub == nil {
+		pubsub = NewInProcessPubSub()
+	}
+	hub = NewHub(pubsub)
+}
+
+// Default returns the package-level Hub configured by Init, for the
+// handler layer to serve WebSocket upgrades through.
+func Default() *Hub {
+	return hub
+}
+
+// Publish sends an event to every connected device of a user. Services
+// call this after a mutation commits; failures to marshal are logged and
+// otherwise swallowed, since a realtime push is a best-effort notification,
+// never the source of truth for the mutation itself.
+func Publish(userID uuid.UUID, eventType string, payload interface{}) {
+	if hub == nil {
+		return
+	}
+
+	data, err := json.Marshal(Event{Type: eventType, Payload: payload})
+	if err != nil {
+		log.Printf("realtime: failed to marshal event %s: %v", eventType, err)
+		return
+	}
+
+	hub.pubsub.Publish(userID.String(), data)
+}
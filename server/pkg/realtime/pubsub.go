@@ -0,0 +1,65 @@
+package realtime
+
+import "sync"
+
+// PubSub is the fan-out primitive the Hub is built on. The default
+// implementation keeps everything in one process; swapping in a
+// Redis-backed PubSub (publish over a channel per user, subscribe via
+// PSUBSCRIBE) is what lets the hub work across multiple server instances
+// without touching Hub or Client at all.
+type PubSub interface {
+	// Publish delivers data to every current subscriber of room.
+	Publish(room string, data []byte)
+	// Subscribe returns a channel of messages published to room and an
+	// unsubscribe func the caller must call when done.
+	Subscribe(room string) (messages <-chan []byte, unsubscribe func())
+}
+
+// InProcessPubSub is the default PubSub: an in-memory fan-out keyed by
+// room (here, a user ID), with no cross-process delivery.
+type InProcessPubSub struct {
+	mu   sync.RWMutex
+	subs map[string]map[chan []byte]struct{}
+}
+
+func NewInProcessPubSub() *InProcessPubSub {
+	return &InProcessPubSub{subs: make(map[string]map[chan []byte]struct{})}
+}
+
+func (p *InProcessPubSub) Publish(room string, data []byte) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for ch := range p.subs[room] {
+		select {
+		case ch <- data:
+		default:
+			// Subscriber's buffer is full; Hub.Serve is responsible
+			// for dropping slow connections, so we just skip this message
+			// rather than block the publisher.
+		}
+	}
+}
+
+func (p *InProcessPubSub) Subscribe(room string) (<-chan []byte, func()) {
+	ch := make(chan []byte, 16)
+
+	p.mu.Lock()
+	if p.subs[room] == nil {
+		p.subs[room] = make(map[chan []byte]struct{})
+	}
+	p.subs[room][ch] = struct{}{}
+	p.mu.Unlock()
+
+	unsubscribe := func() {
+		p.mu.Lock()
+		delete(p.subs[room], ch)
+		if len(p.subs[room]) == 0 {
+			delete(p.subs, room)
+		}
+		p.mu.Unlock()
+		close(ch)
+	}
+
+	return ch, unsubscribe
+}
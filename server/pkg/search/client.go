@@ -0,0 +1,144 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+var errNotConfigured = errors.New("search: elasticsearch is not configured")
+
+type opKind int
+
+const (
+	opIndex opKind = iota
+	opDelete
+)
+
+type retryOp struct {
+	kind opKind
+	doc  TaskDocument
+	id   string
+}
+
+// retryQueueSize bounds the backlog of failed writes kept for retry; past
+// this the oldest pending op is dropped rather than growing unbounded
+// while ES is down.
+const retryQueueSize = 1000
+
+const retryInterval = 30 * time.Second
+
+type esClient struct {
+	es    *elasticsearch.Client
+	index string
+	retry chan retryOp
+}
+
+func newESClient(addresses []string, index string) (*esClient, error) {
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: addresses})
+	if err != nil {
+		return nil, fmt.Errorf("search: failed to create elasticsearch client: %w", err)
+	}
+
+	return &esClient{
+		es:    es,
+		index: index,
+		retry: make(chan retryOp, retryQueueSize),
+	}, nil
+}
+
+func (c *esClient) indexDoc(ctx context.Context, doc TaskDocument) error {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.es.Index(
+		c.index,
+		bytes.NewReader(body),
+		c.es.Index.WithDocumentID(doc.ID),
+		c.es.Index.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("index failed: %s: %s", resp.Status(), b)
+	}
+	return nil
+}
+
+func (c *esClient) delete(ctx context.Context, id string) error {
+	resp, err := c.es.Delete(c.index, id, c.es.Delete.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() && resp.StatusCode != 404 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("delete failed: %s: %s", resp.Status(), b)
+	}
+	return nil
+}
+
+// enqueueRetry stashes a failed write for the background worker to replay,
+// dropping the oldest entry if the queue is full so a prolonged ES outage
+// cannot block request-handling goroutines.
+func (c *esClient) enqueueRetry(op retryOp) {
+	select {
+	case c.retry <- op:
+	default:
+		select {
+		case <-c.retry:
+		default:
+		}
+		c.retry <- op
+	}
+}
+
+// startRetryWorker periodically drains the retry queue, re-attempting
+// queued writes against Elasticsearch in order; the first write that fails
+// again is re-queued and the rest wait for the next tick.
+func (c *esClient) startRetryWorker() {
+	go func() {
+		ticker := time.NewTicker(retryInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			c.drainRetryQueue()
+		}
+	}()
+}
+
+func (c *esClient) drainRetryQueue() {
+	ctx := context.Background()
+	for {
+		select {
+		case op := <-c.retry:
+			var err error
+			switch op.kind {
+			case opIndex:
+				err = c.indexDoc(ctx, op.doc)
+			case opDelete:
+				err = c.delete(ctx, op.id)
+			}
+			if err != nil {
+				log.Printf("search: retry failed, re-queueing: %v", err)
+				c.enqueueRetry(op)
+				return
+			}
+		default:
+			return
+		}
+	}
+}
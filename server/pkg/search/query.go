@@ -0,0 +1,97 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+func (c *esClient) search(ctx context.Context, q Query) ([]SearchHit, error) {
+	must := []map[string]interface{}{
+		{"term": map[string]interface{}{"user_id": q.UserID}},
+	}
+
+	if q.Text != "" {
+		must = append(must, map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  q.Text,
+				"fields": []string{"title^2", "content"},
+			},
+		})
+	}
+	if q.ListID != "" {
+		must = append(must, map[string]interface{}{"term": map[string]interface{}{"list_id": q.ListID}})
+	}
+	if q.Tag != "" {
+		must = append(must, map[string]interface{}{"term": map[string]interface{}{"tags": q.Tag}})
+	}
+	if q.DueBefore != nil {
+		must = append(must, map[string]interface{}{
+			"range": map[string]interface{}{"due_at": map[string]interface{}{"lte": q.DueBefore}},
+		})
+	}
+
+	body := map[string]interface{}{
+		"query": map[string]interface{}{"bool": map[string]interface{}{"must": must}},
+		"highlight": map[string]interface{}{
+			"fields": map[string]interface{}{
+				"title":   map[string]interface{}{},
+				"content": map[string]interface{}{},
+			},
+		},
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.es.Search(
+		c.es.Search.WithContext(ctx),
+		c.es.Search.WithIndex(c.index),
+		c.es.Search.WithBody(bytes.NewReader(encoded)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("search failed: %s: %s", resp.Status(), b)
+	}
+
+	var decoded struct {
+		Hits struct {
+			Hits []struct {
+				Score     float64             `json:"_score"`
+				Source    TaskDocument         `json:"_source"`
+				Highlight map[string][]string `json:"highlight"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	hits := make([]SearchHit, 0, len(decoded.Hits.Hits))
+	for _, h := range decoded.Hits.Hits {
+		hits = append(hits, SearchHit{
+			Task:      h.Source,
+			Highlight: firstHighlight(h.Highlight),
+			Score:     h.Score,
+		})
+	}
+	return hits, nil
+}
+
+func firstHighlight(highlight map[string][]string) string {
+	for _, field := range []string{"title", "content"} {
+		if snippets, ok := highlight[field]; ok && len(snippets) > 0 {
+			return snippets[0]
+		}
+	}
+	return ""
+}
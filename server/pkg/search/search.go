@@ -0,0 +1,102 @@
+// Package search mirrors tasks into an Elasticsearch index so they can be
+// queried with full-text relevance and highlighting. When no ES address
+// is configured, Enabled() reports false and the service layer is
+// expected to fall back to a Postgres-native search instead.
+package search
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// TaskDocument is the search-index shape of a task. It is intentionally
+// independent of model.Task so this package never imports internal/model
+// (model.Task's GORM hooks call into this package, and importing model
+// back here would create a cycle).
+type TaskDocument struct {
+	ID          string     `json:"id"`
+	ListID      string     `json:"list_id"`
+	UserID      string     `json:"user_id"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	Tags        []string   `json:"tags"`
+	Priority    int        `json:"priority"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// SearchHit is one result row, with the matched snippet highlighted by ES.
+type SearchHit struct {
+	Task      TaskDocument `json:"task"`
+	Highlight string       `json:"highlight"`
+	Score     float64      `json:"score"`
+}
+
+// Query narrows a search to one user's tasks, optionally further filtered.
+type Query struct {
+	UserID    string
+	Text      string
+	ListID    string
+	Tag       string
+	DueBefore *time.Time
+}
+
+var client *esClient
+
+// Init configures the package-level ES client. Called once at startup;
+// if addresses is empty the package stays disabled: IndexTask and
+// DeleteTask are no-ops and Search returns an error, letting the service
+// check Enabled() and fall back to Postgres search transparently.
+func Init(addresses []string, index string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	c, err := newESClient(addresses, index)
+	if err != nil {
+		return err
+	}
+	client = c
+	client.startRetryWorker()
+	return nil
+}
+
+// Enabled reports whether Elasticsearch is configured.
+func Enabled() bool {
+	return client != nil
+}
+
+// IndexTask upserts a task document. Failures are queued for retry rather
+// than surfaced, since indexing must never block the request that wrote
+// the task to Postgres.
+func IndexTask(doc TaskDocument) {
+	if client == nil {
+		return
+	}
+	if err := client.indexDoc(context.Background(), doc); err != nil {
+		log.Printf("search: index task %s failed, queueing retry: %v", doc.ID, err)
+		client.enqueueRetry(retryOp{kind: opIndex, doc: doc})
+	}
+}
+
+// DeleteTask removes a task document by ID.
+func DeleteTask(id string) {
+	if client == nil {
+		return
+	}
+	if err := client.delete(context.Background(), id); err != nil {
+		log.Printf("search: delete task %s failed, queueing retry: %v", id, err)
+		client.enqueueRetry(retryOp{kind: opDelete, id: id})
+	}
+}
+
+// Search runs a multi-match query over title/content, filtered to the
+// given user (and optionally list/tag/due date), with highlighted
+// snippets of the matched fields.
+func Search(ctx context.Context, q Query) ([]SearchHit, error) {
+	if client == nil {
+		return nil, errNotConfigured
+	}
+	return client.search(ctx, q)
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// localDriver stores blobs directly on disk under BaseDir and serves them
+// back through BaseURL + key; it has no concept of expiring links, so
+// PresignGet just returns the same stable URL.
+type localDriver struct {
+	baseDir string
+	baseURL string
+}
+
+func newLocalDriver(baseDir, baseURL string) *localDriver {
+	return &localDriver{baseDir: baseDir, baseURL: baseURL}
+}
+
+func (d *localDriver) Put(_ context.Context, key string, r io.Reader, _ string) (string, error) {
+	path, err := d.resolve(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return "", err
+	}
+	return d.baseURL + "/" + key, nil
+}
+
+func (d *localDriver) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := d.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (d *localDriver) Delete(_ context.Context, key string) error {
+	path, err := d.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (d *localDriver) PresignGet(_ context.Context, key string, _ time.Duration) (string, error) {
+	return d.baseURL + "/" + key, nil
+}
+
+// resolve joins key onto baseDir. The key is cleaned against a virtual
+// root first, so ".." segments cannot climb out of baseDir; no key is
+// rejected.
+func (d *localDriver) resolve(key string) (string, error) {
+	path := filepath.Join(d.baseDir, filepath.Clean("/"+key))
+	return path, nil
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Driver talks to any S3-compatible endpoint (AWS S3 or MinIO) through
+// the minio-go client, which both vendors support.
+type s3Driver struct {
+	client *minio.Client
+	bucket string
+}
+
+func newS3Driver(cfg Config) (*s3Driver, error) {
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKeyID, cfg.S3SecretAccessKey, ""),
+		Secure: cfg.S3UseSSL,
+		Region: cfg.S3Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s3Driver{client: client, bucket: cfg.S3Bucket}, nil
+}
+
+func (d *s3Driver) Put(ctx context.Context, key string, r io.Reader, contentType string) (string, error) {
+	_, err := d.client.PutObject(ctx, d.bucket, key, r, -1, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return "", err
+	}
+	return d.client.EndpointURL().String() + "/" + d.bucket + "/" + key, nil
+}
+
+func (d *s3Driver) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return d.client.GetObject(ctx, d.bucket, key, minio.GetObjectOptions{})
+}
+
+func (d *s3Driver) Delete(ctx context.Context, key string) error {
+	return d.client.RemoveObject(ctx, d.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (d *s3Driver) PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	u, err := d.client.PresignedGetObject(ctx, d.bucket, key, expiry, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
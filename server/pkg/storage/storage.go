@@ -0,0 +1,64 @@
+// Package storage abstracts blob storage behind a Driver interface, with
+// local filesystem and S3-compatible (MinIO/AWS) implementations selected
+// via config.AppConfig.StorageDriver.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Driver is implemented by every supported storage backend.
+type Driver interface {
+	// Put uploads r under key and returns a URL usable to reach it (a
+	// direct URL for the local driver, the object's plain S3 URL for the
+	// S3 driver — callers that need time-limited access use PresignGet).
+	Put(ctx context.Context, key string, r io.Reader, contentType string) (url string, err error)
+	// Get opens the object for streaming. Callers must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a time-limited URL for direct client download.
+	PresignGet(ctx context.Context, key string, expiry time.Duration) (url string, err error)
+}
+
+var driver Driver
+
+// Init selects and configures the package-level Driver from name.
+func Init(name string, cfg Config) error {
+	switch name {
+	case "local":
+		driver = newLocalDriver(cfg.LocalBaseDir, cfg.LocalBaseURL)
+		return nil
+	case "s3":
+		d, err := newS3Driver(cfg)
+		if err != nil {
+			return err
+		}
+		driver = d
+		return nil
+	default:
+		return fmt.Errorf("storage: unknown driver %q", name)
+	}
+}
+
+// Config carries every backend's settings; only the fields relevant to
+// the selected driver are read.
+type Config struct {
+	LocalBaseDir string
+	LocalBaseURL string
+
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3UseSSL          bool
+}
+
+// Default returns the package-level Driver configured by Init.
+func Default() Driver {
+	return driver
+}
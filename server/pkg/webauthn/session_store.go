@@ -0,0 +1,79 @@
+package webauthn
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// NewChallengeCookie generates a random, URL-safe value to hand out as the
+// cookie correlating a ceremony's begin and finish calls.
+func NewChallengeCookie() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ceremonyTTL mirrors the challenge timeout WebAuthn clients use; a
+// register/login ceremony should complete within a couple of minutes.
+const ceremonyTTL = 5 * time.Minute
+
+type sessionEntry struct {
+	data      *webauthn.SessionData
+	expiresAt time.Time
+}
+
+// SessionStore is an in-memory, TTL'd map from a challenge cookie value
+// (32 random bytes from NewChallengeCookie; the value is not signed) to
+// the SessionData a begin call produced, so the matching finish
+// call can validate the authenticator's response against it.
+type SessionStore struct {
+	mu      sync.Mutex
+	entries map[string]sessionEntry
+}
+
+func NewSessionStore() *SessionStore {
+	s := &SessionStore{entries: make(map[string]sessionEntry)}
+	go s.sweepLoop()
+	return s
+}
+
+func (s *SessionStore) Put(cookieValue string, data *webauthn.SessionData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[cookieValue] = sessionEntry{data: data, expiresAt: time.Now().Add(ceremonyTTL)}
+}
+
+// Take validates and removes the session data for cookieValue.
+func (s *SessionStore) Take(cookieValue string) (*webauthn.SessionData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[cookieValue]
+	delete(s.entries, cookieValue)
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, errors.New("验证会话已过期，请重新发起")
+	}
+	return entry.data, nil
+}
+
+func (s *SessionStore) sweepLoop() {
+	ticker := time.NewTicker(ceremonyTTL)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		s.mu.Lock()
+		for key, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,31 @@
+// Package webauthn wraps github.com/go-webauthn/webauthn with the bits
+// snail-server needs: a singleton configured from config.AppConfig, and a
+// TTL cache for the SessionData each ceremony carries between its
+// begin/finish calls.
+package webauthn
+
+import (
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+var instance *webauthn.WebAuthn
+
+// Init configures the package-level WebAuthn instance. Must be called
+// once at startup, mirroring pkg/jwt.Init and email.Init.
+func Init(rpID, rpDisplayName string, rpOrigins []string) error {
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: rpDisplayName,
+		RPOrigins:     rpOrigins,
+	})
+	if err != nil {
+		return err
+	}
+	instance = w
+	return nil
+}
+
+// Instance returns the configured WebAuthn singleton.
+func Instance() *webauthn.WebAuthn {
+	return instance
+}